@@ -0,0 +1,69 @@
+package humanlog
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+)
+
+// CallerMarshalFunc formats a caller location, as produced by runtime.Caller
+// or a [log/slog.Record]'s PC, for display.
+type CallerMarshalFunc func(pc uintptr, file string, line int) string
+
+// DefaultCallerMarshalFunc renders the short file name and line, e.g. "foo.go:123".
+func DefaultCallerMarshalFunc(_ uintptr, file string, line int) string {
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+}
+
+var callerColor = color.New(color.Faint)
+
+const callerFieldKey = "\x00caller"
+
+// writeCaller writes caller, if any, in a fixed, dimly colored column right
+// after the level symbol.
+func (f *formatter) writeCaller(caller string) error {
+	if caller == "" {
+		return nil
+	}
+	sw := runewidth.StringWidth(caller)
+	kstat := f.lengths[callerFieldKey]
+	kstat.MaxLength = max(kstat.MaxLength, sw)
+	kstat.Count++
+	if sw+20 < kstat.MaxLength {
+		kstat.MaxLength = sw
+	}
+	f.lengths[callerFieldKey] = kstat
+	pad := strings.Repeat(" ", kstat.MaxLength-sw)
+	_, err := callerColor.Fprintf(f.buf, " %s%s", caller, pad)
+	return err
+}
+
+// callerString renders val using marshal: a "file:line" string is split and
+// passed to marshal (any other string is used as-is), a uintptr is treated
+// as a program counter and resolved first.
+func callerString(marshal CallerMarshalFunc, val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		if i := strings.LastIndexByte(v, ':'); i > 0 {
+			if line, err := strconv.Atoi(v[i+1:]); err == nil {
+				return marshal(0, v[:i], line)
+			}
+		}
+		return v
+	case uintptr:
+		return marshalPC(marshal, v)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// marshalPC resolves pc to a file and line and renders it with marshal.
+func marshalPC(marshal CallerMarshalFunc, pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return marshal(pc, frame.File, frame.Line)
+}
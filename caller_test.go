@@ -0,0 +1,93 @@
+package humanlog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/apex/log"
+)
+
+func TestHandlerShowCaller(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf)
+	h.Format = FormatText
+	h.ShowCaller = true
+	err := h.HandleLog(&log.Entry{
+		Timestamp: testTime,
+		Level:     log.InfoLevel,
+		Message:   "hi",
+		Fields:    log.Fields{"source": "foo.go:10", "other": "x"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "12:00:00.000 I hi foo.go:10 other=x\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ShowCaller=true output = %q, want %q", got, want)
+	}
+}
+
+func TestHandlerShowCallerDisabledKeepsField(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf)
+	h.Format = FormatText
+	err := h.HandleLog(&log.Entry{
+		Timestamp: testTime,
+		Level:     log.InfoLevel,
+		Message:   "hi",
+		Fields:    log.Fields{"caller": "foo.go:10", "other": "x"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "12:00:00.000 I hi caller=foo.go:10 other=x\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ShowCaller=false output = %q, want %q (field must not be dropped)", got, want)
+	}
+}
+
+func TestSlogHandlerShowCallerFromPC(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSlog(&buf)
+	h.Format = FormatText
+	h.ShowCaller = true
+	h.CallerMarshalFunc = func(_ uintptr, file string, line int) string {
+		return "resolved.go:1"
+	}
+	r := slogRecord(testTime, slog.LevelInfo, "hi")
+	r.PC = callerPC()
+	err := h.Handle(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "12:00:00.000 I hi resolved.go:1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ShowCaller from PC output = %q, want %q", got, want)
+	}
+}
+
+func TestSlogHandlerShowCallerPrefersSourceAttr(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSlog(&buf)
+	h.Format = FormatText
+	h.ShowCaller = true
+	r := slogRecord(testTime, slog.LevelInfo, "hi", slog.String("source", "foo.go:10"))
+	r.PC = callerPC()
+	err := h.Handle(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "12:00:00.000 I hi foo.go:10\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ShowCaller with source attr output = %q, want %q", got, want)
+	}
+}
+
+// callerPC returns a non-zero program counter for use as a slog.Record.PC.
+func callerPC() uintptr {
+	pc, _, _, _ := runtime.Caller(0)
+	return pc
+}
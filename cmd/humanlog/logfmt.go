@@ -0,0 +1,58 @@
+package main
+
+import "strings"
+
+// parseLogfmt decodes a single logfmt-encoded line into a flat field map.
+// Bare keys (no "=") are treated as boolean true; within a quoted value,
+// only "\" followed by the quote character or another "\" is treated as an
+// escape sequence, so unrelated backslashes (e.g. in a Windows path) pass
+// through unchanged.
+func parseLogfmt(line string) map[string]interface{} {
+	fields := map[string]interface{}{}
+	i, n := 0, len(line)
+	for i < n {
+		for i < n && line[i] == ' ' {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		start := i
+		for i < n && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			i++
+			continue
+		}
+		if i >= n || line[i] != '=' {
+			fields[key] = true
+			continue
+		}
+		i++ // skip '='
+		if i < n && (line[i] == '"' || line[i] == '\'') {
+			quote := line[i]
+			i++
+			var sb strings.Builder
+			for i < n && line[i] != quote {
+				if line[i] == '\\' && i+1 < n && (line[i+1] == quote || line[i+1] == '\\') {
+					i++
+				}
+				sb.WriteByte(line[i])
+				i++
+			}
+			if i < n {
+				i++ // skip closing quote
+			}
+			fields[key] = sb.String()
+			continue
+		}
+		start = i
+		for i < n && line[i] != ' ' {
+			i++
+		}
+		fields[key] = line[start:i]
+	}
+	return fields
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLogfmt(t *testing.T) {
+	cases := []struct {
+		name string
+		line string
+		want map[string]interface{}
+	}{
+		{
+			name: "bare key",
+			line: "debug",
+			want: map[string]interface{}{"debug": true},
+		},
+		{
+			name: "mixed keys",
+			line: `level=info msg="hello world" user=bob`,
+			want: map[string]interface{}{"level": "info", "msg": "hello world", "user": "bob"},
+		},
+		{
+			name: "single quotes",
+			line: `msg='hello world'`,
+			want: map[string]interface{}{"msg": "hello world"},
+		},
+		{
+			name: "escaped quote",
+			line: `msg="say \"hi\""`,
+			want: map[string]interface{}{"msg": `say "hi"`},
+		},
+		{
+			name: "unrelated backslashes survive",
+			line: `path="C:\Users\foo"`,
+			want: map[string]interface{}{"path": `C:\Users\foo`},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := parseLogfmt(c.line)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("parseLogfmt(%q) = %#v, want %#v", c.line, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Command humanlog reformats newline-delimited JSON or logfmt log streams
+// read from stdin into the colored columnar output of [humanlog.Handler]
+// when stdout is a terminal, and into logfmt otherwise, making it usable as
+// a pipe target (myapp | humanlog).
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/fatih/color"
+	"github.com/nochso/humanlog"
+)
+
+var (
+	formatFlag    = flag.String("format", "auto", "input format: auto, json, logfmt")
+	timestampFlag = flag.String("timestamp", "15:04:05.000", "output timestamp layout")
+	noColorFlag   = flag.Bool("no-color", false, "disable ANSI colors")
+	levelFlag     = flag.String("level", "", "minimum level to show (debug, info, warn, error, fatal)")
+	keepFlag      = flag.String("keep", "", "comma-separated list of fields to print, dropping all others")
+	dropFlag      = flag.String("drop", "", "comma-separated list of fields to drop")
+)
+
+// timeKeys, levelKeys, msgKeys and callerKeys are the common field names
+// mapped onto a [github.com/apex/log.Entry]'s well-known fields.
+var (
+	timeKeys   = []string{"time", "ts", "@timestamp"}
+	levelKeys  = []string{"level", "lvl", "severity"}
+	msgKeys    = []string{"msg", "message"}
+	callerKeys = []string{"caller", "source"}
+)
+
+var levelNames = map[string]log.Level{
+	"debug":   log.DebugLevel,
+	"dbg":     log.DebugLevel,
+	"info":    log.InfoLevel,
+	"warn":    log.WarnLevel,
+	"warning": log.WarnLevel,
+	"error":   log.ErrorLevel,
+	"err":     log.ErrorLevel,
+	"fatal":   log.FatalLevel,
+	"panic":   log.FatalLevel,
+	"crit":    log.FatalLevel,
+}
+
+func main() {
+	flag.Parse()
+
+	var minLevel *log.Level
+	if *levelFlag != "" {
+		lvl, ok := levelNames[strings.ToLower(*levelFlag)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "humanlog: unknown level %q\n", *levelFlag)
+			os.Exit(2)
+		}
+		minLevel = &lvl
+	}
+	var keep, drop map[string]bool
+	if *keepFlag != "" {
+		keep = toSet(strings.Split(*keepFlag, ","))
+	}
+	if *dropFlag != "" {
+		drop = toSet(strings.Split(*dropFlag, ","))
+	}
+	if *noColorFlag {
+		color.NoColor = true
+	}
+
+	h := humanlog.New(os.Stdout)
+	h.Timestamp = *timestampFlag
+
+	if err := run(os.Stdin, h, *formatFlag, minLevel, keep, drop); err != nil {
+		fmt.Fprintln(os.Stderr, "humanlog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(r *os.File, h *humanlog.Handler, format string, minLevel *log.Level, keep, drop map[string]bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			fmt.Println(line)
+			continue
+		}
+		lineFormat := format
+		if lineFormat == "auto" {
+			lineFormat = detectFormat(line)
+		}
+		e, ok := parseLine(line, lineFormat)
+		if !ok {
+			fmt.Println(line)
+			continue
+		}
+		if minLevel != nil && e.Level < *minLevel {
+			continue
+		}
+		filterFields(e, keep, drop)
+		if err := h.HandleLog(e); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func detectFormat(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return "json"
+	}
+	return "logfmt"
+}
+
+func parseLine(line, format string) (*log.Entry, bool) {
+	var fields map[string]interface{}
+	switch format {
+	case "json":
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			return nil, false
+		}
+	case "logfmt":
+		fields = parseLogfmt(line)
+		if len(fields) == 0 {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	return entryFromFields(fields), true
+}
+
+func entryFromFields(fields map[string]interface{}) *log.Entry {
+	e := &log.Entry{Fields: log.Fields{}, Level: log.InfoLevel}
+	for k, v := range fields {
+		switch {
+		case slices.Contains(timeKeys, k):
+			e.Timestamp = parseTime(v)
+		case slices.Contains(levelKeys, k):
+			if lvl, ok := levelNames[strings.ToLower(fmt.Sprint(v))]; ok {
+				e.Level = lvl
+			} else {
+				e.Level = log.InfoLevel
+			}
+		case slices.Contains(msgKeys, k):
+			e.Message = fmt.Sprint(v)
+		case slices.Contains(callerKeys, k):
+			e.Fields["source"] = v
+		default:
+			e.Fields[k] = v
+		}
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	return e
+}
+
+func parseTime(v interface{}) time.Time {
+	switch t := v.(type) {
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed
+			}
+		}
+	case json.Number:
+		if f, err := t.Float64(); err == nil {
+			return unixFromFloat(f)
+		}
+	case float64:
+		return unixFromFloat(t)
+	}
+	return time.Time{}
+}
+
+func unixFromFloat(f float64) time.Time {
+	if f > 1e12 {
+		return time.UnixMilli(int64(f))
+	}
+	return time.Unix(int64(f), 0)
+}
+
+func filterFields(e *log.Entry, keep, drop map[string]bool) {
+	if len(keep) == 0 && len(drop) == 0 {
+		return
+	}
+	for name := range e.Fields {
+		if len(keep) > 0 && !keep[name] {
+			delete(e.Fields, name)
+			continue
+		}
+		if drop[name] {
+			delete(e.Fields, name)
+		}
+	}
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" {
+			set[n] = true
+		}
+	}
+	return set
+}
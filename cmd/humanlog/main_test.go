@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/apex/log"
+)
+
+func TestDetectFormat(t *testing.T) {
+	cases := []struct {
+		line string
+		want string
+	}{
+		{`{"msg":"hi"}`, "json"},
+		{`  {"msg":"hi"}`, "json"},
+		{`msg=hi level=info`, "logfmt"},
+	}
+	for _, c := range cases {
+		if got := detectFormat(c.line); got != c.want {
+			t.Errorf("detectFormat(%q) = %q, want %q", c.line, got, c.want)
+		}
+	}
+}
+
+func TestEntryFromFieldsLevelDefault(t *testing.T) {
+	// A line with no level key at all must not be treated as debug-level;
+	// it should default to info like an unrecognized level value does.
+	e := entryFromFields(map[string]interface{}{"msg": "hi"})
+	if e.Level != log.InfoLevel {
+		t.Errorf("Level with no level field = %v, want %v", e.Level, log.InfoLevel)
+	}
+
+	e = entryFromFields(map[string]interface{}{"level": "bogus"})
+	if e.Level != log.InfoLevel {
+		t.Errorf("Level with unrecognized value = %v, want %v", e.Level, log.InfoLevel)
+	}
+
+	e = entryFromFields(map[string]interface{}{"level": "error"})
+	if e.Level != log.ErrorLevel {
+		t.Errorf("Level with \"error\" = %v, want %v", e.Level, log.ErrorLevel)
+	}
+}
+
+func TestEntryFromFieldsMapsWellKnownKeys(t *testing.T) {
+	e := entryFromFields(map[string]interface{}{
+		"msg":    "hello",
+		"caller": "foo.go:1",
+		"user":   "bob",
+	})
+	if e.Message != "hello" {
+		t.Errorf("Message = %q, want %q", e.Message, "hello")
+	}
+	if got := e.Fields.Get("source"); got != "foo.go:1" {
+		t.Errorf("Fields[source] = %v, want %q", got, "foo.go:1")
+	}
+	if got := e.Fields.Get("user"); got != "bob" {
+		t.Errorf("Fields[user] = %v, want %q", got, "bob")
+	}
+}
+
+func TestParseLineJSON(t *testing.T) {
+	e, ok := parseLine(`{"msg":"hi","level":"warn"}`, "json")
+	if !ok {
+		t.Fatal("parseLine returned ok=false for valid JSON")
+	}
+	if e.Message != "hi" || e.Level != log.WarnLevel {
+		t.Errorf("parseLine JSON = %+v, want Message=hi Level=warn", e)
+	}
+}
+
+func TestParseLineInvalidJSON(t *testing.T) {
+	if _, ok := parseLine(`not json`, "json"); ok {
+		t.Error("parseLine(\"not json\", \"json\") ok = true, want false")
+	}
+}
+
+func TestFilterFields(t *testing.T) {
+	e := &log.Entry{Fields: log.Fields{"a": 1, "b": 2, "c": 3}}
+	filterFields(e, map[string]bool{"a": true, "b": true}, map[string]bool{"b": true})
+	if e.Fields.Get("a") == nil {
+		t.Error("expected field a to be kept")
+	}
+	if e.Fields.Get("b") != nil {
+		t.Error("expected field b to be dropped despite being kept, since drop wins")
+	}
+	if e.Fields.Get("c") != nil {
+		t.Error("expected field c to be dropped, not in keep set")
+	}
+}
@@ -0,0 +1,117 @@
+package humanlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Format selects how [Handler] and [SlogHandler] render entries.
+type Format int
+
+const (
+	// FormatAuto renders as FormatText when the Handler's writer is a
+	// terminal, and FormatLogfmt otherwise. This is the zero value.
+	FormatAuto Format = iota
+	// FormatText is the colored, column-aligned format used interactively.
+	FormatText
+	// FormatLogfmt renders each entry as a single logfmt line, quoting
+	// values that contain spaces, "=" or quotes.
+	FormatLogfmt
+	// FormatJSON renders each entry as a single JSON object with "time",
+	// "level" and "msg" keys followed by its fields.
+	FormatJSON
+)
+
+// isTerminal reports whether w is a terminal, for resolving FormatAuto.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && isatty.IsTerminal(file.Fd())
+}
+
+// resolveFormat returns format, resolving FormatAuto against isTTY.
+func resolveFormat(format Format, isTTY bool) Format {
+	if format != FormatAuto {
+		return format
+	}
+	if isTTY {
+		return FormatText
+	}
+	return FormatLogfmt
+}
+
+// writeLogfmt renders a single logfmt line to f.Writer. get looks up a
+// field's value by name, as [github.com/apex/log.Fields.Get] and a
+// slog attribute map both do.
+func (f *formatter) writeLogfmt(ts time.Time, level, msg string, names []string, get func(string) interface{}, humanizers map[string]func(interface{}) interface{}, auto bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.buf.Reset()
+	writeLogfmtPair(f.buf, "time", ts.Format(time.RFC3339Nano), true)
+	writeLogfmtPair(f.buf, "level", level, false)
+	writeLogfmtPair(f.buf, "msg", msg, false)
+	for _, name := range names {
+		val := humanizeValue(name, get(name), humanizers, auto)
+		writeLogfmtPair(f.buf, name, val, false)
+	}
+	fmt.Fprintln(f.buf)
+	_, err := f.buf.WriteTo(f.Writer)
+	return err
+}
+
+// writeLogfmtPair writes "key=value" to buf, preceded by a space unless
+// first, quoting value per the Heroku logfmt rules if it is empty or
+// contains a space, "=" or quote.
+func writeLogfmtPair(buf *bytes.Buffer, key string, val interface{}, first bool) {
+	s := fmt.Sprintf("%v", val)
+	if !first {
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	if s == "" || strings.ContainsAny(s, ` ="`) {
+		buf.WriteString(strconv.Quote(s))
+		return
+	}
+	buf.WriteString(s)
+}
+
+// writeJSON renders a single JSON object to f.Writer. get looks up a
+// field's value by name, as [github.com/apex/log.Fields.Get] and a
+// slog attribute map both do.
+func (f *formatter) writeJSON(ts time.Time, level, msg string, names []string, get func(string) interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.buf.Reset()
+	f.buf.WriteByte('{')
+	writeJSONPair(f.buf, "time", ts.Format(time.RFC3339Nano), true)
+	writeJSONPair(f.buf, "level", level, false)
+	writeJSONPair(f.buf, "msg", msg, false)
+	for _, name := range names {
+		writeJSONPair(f.buf, name, get(name), false)
+	}
+	f.buf.WriteString("}\n")
+	_, err := f.buf.WriteTo(f.Writer)
+	return err
+}
+
+// writeJSONPair appends ,"key":value (or "key":value if first) to buf.
+func writeJSONPair(buf *bytes.Buffer, key string, val interface{}, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%v", val))
+	}
+	fmt.Fprintf(buf, "%q:%s", key, b)
+}
@@ -0,0 +1,155 @@
+package humanlog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/apex/log"
+)
+
+var testTime = time.Date(2026, 7, 30, 12, 0, 0, 0, time.UTC)
+
+func TestHandlerWriteLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf)
+	h.Format = FormatLogfmt
+	err := h.HandleLog(&log.Entry{
+		Timestamp: testTime,
+		Level:     log.WarnLevel,
+		Message:   "disk low",
+		Fields:    log.Fields{"user": "bob smith", "free": "3.2GB"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `time=2026-07-30T12:00:00Z level=warn msg="disk low" free=3.2GB user="bob smith"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeLogfmt output = %q, want %q", got, want)
+	}
+}
+
+func TestHandlerWriteLogfmtHumanizesDuration(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf)
+	h.Format = FormatLogfmt
+	err := h.HandleLog(&log.Entry{
+		Timestamp: testTime,
+		Level:     log.InfoLevel,
+		Message:   "done",
+		Fields:    log.Fields{"elapsed": 1500 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `time=2026-07-30T12:00:00Z level=info msg=done elapsed=1.5s` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeLogfmt output = %q, want %q", got, want)
+	}
+}
+
+func TestHandlerWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf)
+	h.Format = FormatJSON
+	err := h.HandleLog(&log.Entry{
+		Timestamp: testTime,
+		Level:     log.ErrorLevel,
+		Message:   "boom",
+		Fields:    log.Fields{"user": "bob"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"time":"2026-07-30T12:00:00Z","level":"error","msg":"boom","user":"bob"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeJSON output = %q, want %q", got, want)
+	}
+}
+
+func TestHandlerWriteJSONRendersDurationAsNanoseconds(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf)
+	h.Format = FormatJSON
+	err := h.HandleLog(&log.Entry{
+		Timestamp: testTime,
+		Level:     log.InfoLevel,
+		Message:   "done",
+		Fields:    log.Fields{"elapsed": 1500 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"time":"2026-07-30T12:00:00Z","level":"info","msg":"done","elapsed":1500000000}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeJSON output = %q, want %q", got, want)
+	}
+}
+
+func TestWriteLogfmtPairQuoting(t *testing.T) {
+	cases := []struct {
+		val  interface{}
+		want string
+	}{
+		{"bob", "k=bob"},
+		{"bob smith", `k="bob smith"`},
+		{`a=b`, `k="a=b"`},
+		{`say "hi"`, `k="say \"hi\""`},
+		{"", `k=""`},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		writeLogfmtPair(&buf, "k", c.val, true)
+		if got := buf.String(); got != c.want {
+			t.Errorf("writeLogfmtPair(%q) = %q, want %q", c.val, got, c.want)
+		}
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	cases := []struct {
+		format Format
+		isTTY  bool
+		want   Format
+	}{
+		{FormatAuto, true, FormatText},
+		{FormatAuto, false, FormatLogfmt},
+		{FormatJSON, true, FormatJSON},
+		{FormatText, false, FormatText},
+	}
+	for _, c := range cases {
+		if got := resolveFormat(c.format, c.isTTY); got != c.want {
+			t.Errorf("resolveFormat(%v, %v) = %v, want %v", c.format, c.isTTY, got, c.want)
+		}
+	}
+}
+
+func TestSlogHandlerFormatParity(t *testing.T) {
+	var bufText, bufSlog bytes.Buffer
+	h := New(&bufText)
+	h.Format = FormatJSON
+	h.HandleLog(&log.Entry{
+		Timestamp: testTime,
+		Level:     log.WarnLevel,
+		Message:   "boom",
+		Fields:    log.Fields{"zzz_count": 3, "aaa_name": "bob"},
+	})
+
+	sh := NewSlog(&bufSlog)
+	sh.Format = FormatJSON
+	if err := sh.Handle(context.Background(), slogRecord(testTime, slog.LevelWarn, "boom", slog.Int("zzz_count", 3), slog.String("aaa_name", "bob"))); err != nil {
+		t.Fatal(err)
+	}
+	if bufText.String() != bufSlog.String() {
+		t.Errorf("SlogHandler JSON output = %q, want it to match Handler output %q", bufSlog.String(), bufText.String())
+	}
+}
+
+// slogRecord builds a [log/slog.Record] with attrs for use in tests.
+func slogRecord(ts time.Time, level slog.Level, msg string, attrs ...slog.Attr) slog.Record {
+	r := slog.NewRecord(ts, level, msg, 0)
+	r.AddAttrs(attrs...)
+	return r
+}
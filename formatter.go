@@ -0,0 +1,152 @@
+package humanlog
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	colorable "github.com/mattn/go-colorable"
+	"github.com/mattn/go-runewidth"
+)
+
+// formatter holds the column-width bookkeeping and rendering logic shared
+// between [Handler] and [SlogHandler].
+type formatter struct {
+	mu      sync.Mutex
+	Writer  io.Writer
+	lengths map[string]keyStat
+	buf     *bytes.Buffer
+}
+
+type keyStat struct {
+	MaxLength      int
+	Count          int
+	RightAlignable int
+}
+
+func newFormatter(w io.Writer) *formatter {
+	f := &formatter{
+		lengths: map[string]keyStat{},
+		Writer:  w,
+		buf:     &bytes.Buffer{},
+	}
+	if file, ok := w.(*os.File); ok {
+		f.Writer = colorable.NewColorable(file)
+	}
+	return f
+}
+
+// writeHeader writes the colored timestamp, level symbol and optional
+// message that precede a line's fields.
+func (f *formatter) writeHeader(colr *color.Color, timestamp, level, message string) error {
+	_, err := colr.Fprintf(f.buf, "%s %s", timestamp, level)
+	if err != nil {
+		return err
+	}
+	if message != "" {
+		_, err = fmt.Fprintf(f.buf, " %s", message)
+	}
+	return err
+}
+
+// keyColorConfig bundles a Handler/SlogHandler's key-coloring options for
+// passing through to writeNameValue and getKeyColor.
+type keyColorConfig struct {
+	Mode       KeyColorMode
+	Colors     map[string]*color.Color
+	Background Background
+}
+
+func (f *formatter) writeNameValue(name string, val interface{}, i, n int, humanizers map[string]func(interface{}) interface{}, auto bool, kc keyColorConfig) error {
+	val = humanizeValue(name, val, humanizers, auto)
+	sw := runewidth.StringWidth(fmt.Sprintf("%v", val))
+	kstat := f.lengths[name]
+	kstat.MaxLength = max(kstat.MaxLength, sw)
+	kstat.Count++
+	if sw+20 < kstat.MaxLength {
+		kstat.MaxLength = sw
+	}
+	isRight := f.isTypeRightAlignable(val)
+	if isRight {
+		kstat.RightAlignable++
+	}
+	isRight = kstat.RightAlignable > kstat.Count/2
+	f.lengths[name] = kstat
+	if isRight {
+		_, err := fmt.Fprintf(f.buf, " %s=%*v", f.getKeyColor(name, kc).Sprint(name), kstat.MaxLength, val)
+		return err
+	}
+	var pad string
+	if sw < kstat.MaxLength && i+1 != n {
+		pad = strings.Repeat(" ", kstat.MaxLength-sw)
+	}
+	_, err := fmt.Fprintf(f.buf, " %s=%v%s", f.getKeyColor(name, kc).Sprint(name), val, pad)
+	return err
+}
+
+// sortNames sorts names so right-alignable values (numbers, durations, ...)
+// come first, keyed via get, which looks up a name's current value.
+func (f *formatter) sortNames(names []string, get func(name string) interface{}) []string {
+	sort.Slice(names, func(a, b int) bool {
+		aright := f.isTypeRightAlignable(get(names[a]))
+		bright := f.isTypeRightAlignable(get(names[b]))
+		if aright != bright {
+			return aright
+		}
+		return strings.Compare(names[a], names[b]) == -1
+	})
+	return names
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+var reNumType = regexp.MustCompile(`(?i)^\d+(\.\d+)? ?([a-z]{1,5})?$`)
+
+func (f *formatter) isTypeRightAlignable(x interface{}) bool {
+	switch v := x.(type) {
+	case uint, uint8, uint16, uint32, uint64,
+		int, int8, int16, int32, int64,
+		float32, float64,
+		complex64, complex128,
+		time.Duration, *time.Duration:
+		return true
+	case string:
+		return reNumType.MatchString(v)
+	case []byte:
+		return reNumType.Match(v)
+	default:
+		return false
+	}
+}
+
+// getKeyColor picks key's color: uncolored if kc.Mode is KeyColorNone,
+// else an explicit override from kc.Colors if present, else a color hashed
+// from key using kc.Mode and kc.Background.
+func (f *formatter) getKeyColor(key string, kc keyColorConfig) *color.Color {
+	if kc.Mode == KeyColorNone {
+		return hashedKeyColor(0, kc.Mode, kc.Background)
+	}
+	if c := kc.Colors[key]; c != nil {
+		return c
+	}
+	sum := crc32.ChecksumIEEE([]byte(key))
+	return hashedKeyColor(sum, kc.Mode, kc.Background)
+}
+
+func mapColor(v uint32) byte {
+	v = uint32(byte(v))
+	return byte(v*5/10 + 105)
+}
@@ -0,0 +1,199 @@
+package humanlog
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Bytes renders n as a human-readable size using SI (decimal, base 1000)
+// units, e.g. "1.2 MB". See [BytesIEC] for binary units.
+func Bytes(n int64) string {
+	return humanizeScale(float64(n), 1000, siByteUnits, " ")
+}
+
+// BytesIEC renders n as a human-readable size using IEC (binary, base 1024)
+// units, e.g. "3.4 GiB".
+func BytesIEC(n int64) string {
+	return humanizeScale(float64(n), 1024, iecByteUnits, " ")
+}
+
+var siByteUnits = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+var iecByteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// Count renders n as a human-readable magnitude, e.g. "1.2K", "3.4M".
+func Count(n int64) string {
+	return humanizeScale(float64(n), 1000, countUnits, "")
+}
+
+var countUnits = []string{"", "K", "M", "B", "T"}
+
+func humanizeScale(v, base float64, units []string, sep string) string {
+	i := 0
+	for math.Abs(v) >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f%s%s", v, sep, units[i])
+	}
+	return fmt.Sprintf("%.1f%s%s", v, sep, units[i])
+}
+
+// Rate renders v, a byte count per second, as a human-readable byte rate,
+// e.g. "1.2 MB/s".
+func Rate(v float64) string {
+	return Bytes(int64(v)) + "/s"
+}
+
+// RateBits renders v, a bit count per second, as a human-readable bit
+// rate, e.g. "125.0 Mbit/s".
+func RateBits(v float64) string {
+	return humanizeScale(v, 1000, bitRateUnits, " ") + "/s"
+}
+
+var bitRateUnits = []string{"bit", "Kbit", "Mbit", "Gbit", "Tbit", "Pbit", "Ebit"}
+
+// Ratio renders v (0..1) as a percentage with adaptive precision, e.g.
+// "0.05%", "8.0%", "12%".
+func Ratio(v float64) string {
+	pct := v * 100
+	switch abs := math.Abs(pct); {
+	case abs != 0 && abs < 1:
+		return fmt.Sprintf("%.2f%%", pct)
+	case abs < 10:
+		return fmt.Sprintf("%.1f%%", pct)
+	default:
+		return fmt.Sprintf("%.0f%%", pct)
+	}
+}
+
+// humanizeValue formats val for name: an explicit humanizer from humanizers
+// takes priority, then [Duration] for time.Duration values, then an
+// auto-detected humanizer if auto is set. val is returned unchanged if none
+// apply.
+func humanizeValue(name string, val interface{}, humanizers map[string]func(interface{}) interface{}, auto bool) interface{} {
+	if fn := humanizers[name]; fn != nil {
+		return fn(val)
+	}
+	if dur, ok := val.(time.Duration); ok {
+		return Duration(dur)
+	}
+	if auto {
+		if fn := autoHumanizer(name); fn != nil {
+			return fn(val)
+		}
+	}
+	return val
+}
+
+// autoHumanizer guesses a humanizer for key based on common naming
+// conventions (bytes, size, duration, rate, count, ratio, percent), or
+// returns nil if none apply.
+func autoHumanizer(key string) func(interface{}) interface{} {
+	lower := strings.ToLower(key)
+	switch {
+	case strings.HasSuffix(lower, "_bps"):
+		return humanizeRateBits
+	case strings.HasSuffix(lower, "/s"), strings.Contains(lower, "rate"):
+		return humanizeRate
+	case strings.Contains(lower, "byte"), strings.Contains(lower, "size"):
+		return humanizeBytes
+	case strings.Contains(lower, "duration"):
+		return humanizeDuration
+	case strings.Contains(lower, "ratio"), strings.Contains(lower, "percent"):
+		return humanizeRatio
+	case strings.Contains(lower, "count"):
+		return humanizeCount
+	default:
+		return nil
+	}
+}
+
+func humanizeBytes(v interface{}) interface{} {
+	if n, ok := toInt64(v); ok {
+		return Bytes(n)
+	}
+	return v
+}
+
+func humanizeCount(v interface{}) interface{} {
+	if n, ok := toInt64(v); ok {
+		return Count(n)
+	}
+	return v
+}
+
+func humanizeRate(v interface{}) interface{} {
+	if f, ok := toFloat64(v); ok {
+		return Rate(f)
+	}
+	return v
+}
+
+func humanizeRateBits(v interface{}) interface{} {
+	if f, ok := toFloat64(v); ok {
+		return RateBits(f)
+	}
+	return v
+}
+
+func humanizeRatio(v interface{}) interface{} {
+	if f, ok := toFloat64(v); ok {
+		return Ratio(f)
+	}
+	return v
+}
+
+func humanizeDuration(v interface{}) interface{} {
+	if d, ok := v.(time.Duration); ok {
+		return Duration(d)
+	}
+	return v
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), true
+	case float32:
+		return int64(n), true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		if i, ok := toInt64(v); ok {
+			return float64(i), true
+		}
+		return 0, false
+	}
+}
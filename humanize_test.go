@@ -0,0 +1,129 @@
+package humanlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBytes(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{999, "999 B"},
+		{1000, "1.0 KB"},
+		{1_500_000, "1.5 MB"},
+		{1_000_000_000, "1.0 GB"},
+	}
+	for _, c := range cases {
+		if got := Bytes(c.n); got != c.want {
+			t.Errorf("Bytes(%d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
+
+func TestBytesIEC(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{1 << 30, "1.0 GiB"},
+	}
+	for _, c := range cases {
+		if got := BytesIEC(c.n); got != c.want {
+			t.Errorf("BytesIEC(%d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1200, "1.2K"},
+		{3_400_000, "3.4M"},
+	}
+	for _, c := range cases {
+		if got := Count(c.n); got != c.want {
+			t.Errorf("Count(%d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	if got, want := Rate(1_200_000), "1.2 MB/s"; got != want {
+		t.Errorf("Rate(1200000) = %q, want %q", got, want)
+	}
+}
+
+func TestRateBits(t *testing.T) {
+	// A 125 Mbit/s link (125,000,000 bits/s) must not be reported as if
+	// it were 125 MB/s -- bits and bytes differ by a factor of 8.
+	if got, want := RateBits(125_000_000), "125.0 Mbit/s"; got != want {
+		t.Errorf("RateBits(125000000) = %q, want %q", got, want)
+	}
+}
+
+func TestAutoHumanizerRateSuffix(t *testing.T) {
+	// "_bps" keys are bit rates; generic "rate"/"/s" keys are byte rates.
+	bits := autoHumanizer("net_send_bps")
+	if bits == nil {
+		t.Fatal("autoHumanizer(\"net_send_bps\") = nil, want a humanizer")
+	}
+	if got, want := bits(int64(125_000_000)), "125.0 Mbit/s"; got != want {
+		t.Errorf("autoHumanizer(\"net_send_bps\")(125000000) = %q, want %q", got, want)
+	}
+
+	bytes := autoHumanizer("download_rate")
+	if bytes == nil {
+		t.Fatal("autoHumanizer(\"download_rate\") = nil, want a humanizer")
+	}
+	if got, want := bytes(int64(125_000_000)), "125.0 MB/s"; got != want {
+		t.Errorf("autoHumanizer(\"download_rate\")(125000000) = %q, want %q", got, want)
+	}
+}
+
+func TestRatio(t *testing.T) {
+	cases := []struct {
+		v    float64
+		want string
+	}{
+		{0, "0.0%"},
+		{0.0005, "0.05%"},
+		{0.08, "8.0%"},
+		{0.123, "12%"},
+		{0.999, "100%"},
+	}
+	for _, c := range cases {
+		if got := Ratio(c.v); got != c.want {
+			t.Errorf("Ratio(%v) = %q, want %q", c.v, got, c.want)
+		}
+	}
+}
+
+func TestHumanizeValue(t *testing.T) {
+	humanizers := map[string]func(interface{}) interface{}{
+		"custom": func(v interface{}) interface{} { return "overridden" },
+	}
+
+	if got := humanizeValue("custom", 1, humanizers, true); got != "overridden" {
+		t.Errorf("humanizeValue with explicit humanizer = %v, want %v", got, "overridden")
+	}
+	if got, want := humanizeValue("dur", 1500*time.Millisecond, nil, false), Duration(1500*time.Millisecond); got != want {
+		t.Errorf("humanizeValue(time.Duration) = %v, want %v", got, want)
+	}
+	if got, want := humanizeValue("req_count", int64(1200), nil, true), "1.2K"; got != want {
+		t.Errorf("humanizeValue with AutoHumanize = %v, want %v", got, want)
+	}
+	if got, want := humanizeValue("req_count", int64(1200), nil, false), int64(1200); got != want {
+		t.Errorf("humanizeValue without AutoHumanize = %v, want %v", got, want)
+	}
+}
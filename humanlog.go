@@ -2,21 +2,13 @@
 package humanlog
 
 import (
-	"bytes"
 	"fmt"
-	"hash/crc32"
 	"io"
 	"os"
-	"regexp"
-	"sort"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/apex/log"
 	"github.com/fatih/color"
-	colorable "github.com/mattn/go-colorable"
-	"github.com/mattn/go-runewidth"
 )
 
 // Default handler outputs to stderr.
@@ -42,63 +34,99 @@ var LevelSymbol = [...]string{
 
 // Handler implements [github.com/apex/log.Handler]
 type Handler struct {
-	mu        sync.Mutex
-	Writer    io.Writer
+	*formatter
 	Timestamp string
-	lengths   map[string]keyStat
-	buf       *bytes.Buffer
-}
-
-type keyStat struct {
-	MaxLength      int
-	Count          int
-	RightAlignable int
+	// ShowCaller, when true, renders a "source" or "caller" field in a
+	// fixed column after the level symbol. When false, such a field is
+	// rendered like any other field.
+	ShowCaller bool
+	// CallerMarshalFunc formats the value of a "source"/"caller" field for
+	// display. Defaults to [DefaultCallerMarshalFunc].
+	CallerMarshalFunc CallerMarshalFunc
+	// Humanizers formats a named field's value before it is measured and
+	// rendered, keyed by field name.
+	Humanizers map[string]func(interface{}) interface{}
+	// AutoHumanize picks a humanizer based on key-name heuristics (bytes,
+	// size, duration, rate, count, ratio, percent) for fields with no
+	// matching entry in Humanizers.
+	AutoHumanize bool
+	// Format selects the output format. Defaults to [FormatAuto].
+	Format Format
+	// KeyColorMode selects how field-name colors are rendered. Defaults to
+	// [KeyColorTruecolor].
+	KeyColorMode KeyColorMode
+	// KeyColors overrides the hashed color for specific field names,
+	// keyed by field name. Seeded with an "error" -> red entry.
+	KeyColors map[string]*color.Color
+	// Background selects which curated [KeyColor256]/[KeyColor16] palette
+	// to draw from. Defaults to [BackgroundAuto].
+	Background Background
+
+	isTTY bool
 }
 
 // New return a new [Handler] writing to [w].
 func New(w io.Writer) *Handler {
-	h := &Handler{
-		lengths:   map[string]keyStat{},
-		Writer:    w,
-		buf:       &bytes.Buffer{},
-		Timestamp: "15:04:05.000",
-	}
-	if f, ok := w.(*os.File); ok {
-		h.Writer = colorable.NewColorable(f)
+	return &Handler{
+		formatter:         newFormatter(w),
+		Timestamp:         "15:04:05.000",
+		CallerMarshalFunc: DefaultCallerMarshalFunc,
+		KeyColors:         defaultKeyColors(),
+		isTTY:             isTerminal(w),
 	}
-	return h
 }
 
+// keyColorConfig bundles h's key-coloring options for writeNameValue.
+func (h *Handler) keyColorConfig() keyColorConfig {
+	return keyColorConfig{Mode: h.KeyColorMode, Colors: h.KeyColors, Background: h.Background}
+}
+
+// callerFieldNames are the field names HandleLog checks for caller info.
+var callerFieldNames = [...]string{"source", "caller"}
+
 // HandleLog implements [github.com/apex/log.Handler].
 func (h *Handler) HandleLog(e *log.Entry) error {
+	switch resolveFormat(h.Format, h.isTTY) {
+	case FormatJSON:
+		return h.writeJSON(e.Timestamp, e.Level.String(), e.Message, e.Fields.Names(), e.Fields.Get)
+	case FormatLogfmt:
+		return h.writeLogfmt(e.Timestamp, e.Level.String(), e.Message, e.Fields.Names(), e.Fields.Get, h.Humanizers, h.AutoHumanize)
+	}
+
 	colr := LevelColors[e.Level]
 	level := LevelSymbol[e.Level]
-	names := h.sortNames(e, e.Fields.Names())
+	names := h.sortNames(e.Fields.Names(), e.Fields.Get)
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	h.buf.Reset()
-	_, err := colr.Fprintf(h.buf, "%s %s", e.Timestamp.Format(h.Timestamp), level)
-	if err != nil {
+	if err := h.writeHeader(colr, e.Timestamp.Format(h.Timestamp), level, e.Message); err != nil {
 		return err
 	}
-	if e.Message != "" {
-		_, err = fmt.Fprintf(h.buf, " %s", e.Message)
-		if err != nil {
+	var callerKey string
+	if h.ShowCaller {
+		var caller string
+		for _, key := range callerFieldNames {
+			if val := e.Fields.Get(key); val != nil {
+				callerKey = key
+				caller = callerString(h.CallerMarshalFunc, val)
+				break
+			}
+		}
+		if err := h.writeCaller(caller); err != nil {
 			return err
 		}
 	}
 	for i, name := range names {
-		if name == "source" {
+		if name == callerKey {
 			continue
 		}
-		err = h.writeNameValue(e, name, i, names)
-		if err != nil {
+		if err := h.writeNameValue(name, e.Fields.Get(name), i, len(names), h.Humanizers, h.AutoHumanize, h.keyColorConfig()); err != nil {
 			return err
 		}
 	}
-	_, err = fmt.Fprintln(h.buf)
+	_, err := fmt.Fprintln(h.buf)
 	if err != nil {
 		return err
 	}
@@ -106,91 +134,6 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 	return err
 }
 
-func (h *Handler) writeNameValue(e *log.Entry, name string, i int, names []string) error {
-	val := e.Fields.Get(name)
-	if dur, ok := val.(time.Duration); ok {
-		val = Duration(dur)
-	}
-	sw := runewidth.StringWidth(fmt.Sprintf("%v", val))
-	kstat, _ := h.lengths[name]
-	kstat.MaxLength = max(kstat.MaxLength, sw)
-	kstat.Count++
-	if sw+20 < kstat.MaxLength {
-		kstat.MaxLength = sw
-	}
-	isRight := h.isTypeRightAlignable(val)
-	if isRight {
-		kstat.RightAlignable++
-	}
-	isRight = kstat.RightAlignable > kstat.Count/2
-	h.lengths[name] = kstat
-	if isRight {
-		_, err := fmt.Fprintf(h.buf, " %s=%*v", h.getKeyColor(name).Sprint(name), kstat.MaxLength, val)
-		if err != nil {
-			return err
-		}
-		return nil
-	}
-	var pad string
-	if sw < kstat.MaxLength && i+1 != len(names) {
-		pad = strings.Repeat(" ", kstat.MaxLength-sw)
-	}
-	_, err := fmt.Fprintf(h.buf, " %s=%v%s", h.getKeyColor(name).Sprint(name), val, pad)
-	return err
-}
-
-func (h *Handler) sortNames(e *log.Entry, names []string) []string {
-	sort.Slice(names, func(a, b int) bool {
-		aright := h.isTypeRightAlignable(e.Fields.Get(names[a]))
-		bright := h.isTypeRightAlignable(e.Fields.Get(names[b]))
-		if aright != bright {
-			return aright
-		}
-		return strings.Compare(names[a], names[b]) == -1
-	})
-	return names
-}
-
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-var reNumType = regexp.MustCompile(`(?i)^\d+(\.\d+)? ?([a-z]{1,5})?$`)
-
-func (h *Handler) isTypeRightAlignable(x interface{}) bool {
-	switch v := x.(type) {
-	case uint, uint8, uint16, uint32, uint64,
-		int, int8, int16, int32, int64,
-		float32, float64,
-		complex64, complex128,
-		time.Duration, *time.Duration:
-		return true
-	case string:
-		return reNumType.MatchString(v)
-	case []byte:
-		return reNumType.Match(v)
-	default:
-		return false
-	}
-}
-
-func (h *Handler) getKeyColor(key string) *color.Color {
-	if key == "error" {
-		return LevelColors[log.ErrorLevel]
-	}
-	sum := crc32.ChecksumIEEE([]byte(key))
-	r, g, b := mapColor(sum>>24), mapColor(sum>>16), mapColor(sum>>8)
-	return color.RGB(int(r), int(g), int(b))
-}
-
-func mapColor(v uint32) byte {
-	v = uint32(byte(v))
-	return byte(v*5/10 + 105)
-}
-
 var durations = []struct {
 	d time.Duration
 	s string
@@ -0,0 +1,109 @@
+package humanlog
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/fatih/color"
+)
+
+// KeyColorMode selects how field-name colors are rendered.
+type KeyColorMode int
+
+const (
+	// KeyColorTruecolor hashes each key into a 24-bit RGB color. This is
+	// the zero value; it looks best but requires a truecolor-capable
+	// terminal and can produce low-contrast colors on some backgrounds.
+	KeyColorTruecolor KeyColorMode = iota
+	// KeyColor256 hashes each key into a curated xterm 256-color palette.
+	KeyColor256
+	// KeyColor16 hashes each key into the six non-neutral ANSI colors.
+	KeyColor16
+	// KeyColorNone disables per-key coloring; keys render uncolored.
+	KeyColorNone
+)
+
+// Background selects which of the curated [KeyColor256]/[KeyColor16]
+// palettes to draw from.
+type Background int
+
+const (
+	// BackgroundAuto detects the terminal background from the COLORFGBG
+	// environment variable, falling back to BackgroundDark if it is unset
+	// or unparseable.
+	BackgroundAuto Background = iota
+	// BackgroundDark picks brighter colors suited to a dark background.
+	BackgroundDark
+	// BackgroundLight picks darker colors suited to a light background.
+	BackgroundLight
+)
+
+// resolveBackground resolves bg against COLORFGBG if it is BackgroundAuto.
+func resolveBackground(bg Background) Background {
+	if bg != BackgroundAuto {
+		return bg
+	}
+	// COLORFGBG is "fg;bg" (some terminals add a middle field); 7 and 15
+	// are white/bright-white, i.e. a light background.
+	fields := strings.Split(os.Getenv("COLORFGBG"), ";")
+	if n, err := strconv.Atoi(fields[len(fields)-1]); err == nil && (n == 7 || n == 15) {
+		return BackgroundLight
+	}
+	return BackgroundDark
+}
+
+// defaultKeyColors seeds Handler/SlogHandler.KeyColors with the "error"
+// field's red special case.
+func defaultKeyColors() map[string]*color.Color {
+	return map[string]*color.Color{
+		"error": LevelColors[log.ErrorLevel],
+	}
+}
+
+// keyColor256Dark and keyColor256Light are curated xterm 256-color cube
+// codes, hand-picked to stay legible against a dark or light background
+// respectively without drifting into the low-contrast extremes (near-black,
+// near-white, muddy grays) of the full cube.
+var keyColor256Dark = []int{210, 203, 216, 222, 228, 157, 121, 159, 147, 183, 218, 225}
+var keyColor256Light = []int{124, 94, 58, 22, 24, 53, 90, 130, 166, 136, 61, 25}
+
+// keyColor16Dark and keyColor16Light are the ANSI colors excluding black,
+// white and their bright variants, which are illegible or low-contrast
+// against most backgrounds. Bright variants read better on dark
+// backgrounds; plain variants read better on light ones.
+var keyColor16Dark = []color.Attribute{color.FgHiRed, color.FgHiGreen, color.FgHiYellow, color.FgHiBlue, color.FgHiMagenta, color.FgHiCyan}
+var keyColor16Light = []color.Attribute{color.FgRed, color.FgGreen, color.FgYellow, color.FgBlue, color.FgMagenta, color.FgCyan}
+
+// color256 returns a foreground color using the 256-color SGR sequence
+// ESC[38;5;<n>m.
+func color256(n int) *color.Color {
+	return color.New(color.Attribute(38), color.Attribute(5), color.Attribute(n))
+}
+
+// hashedKeyColor picks a color for key by hashing it into mode's palette,
+// chosen for bg.
+func hashedKeyColor(sum uint32, mode KeyColorMode, bg Background) *color.Color {
+	switch mode {
+	case KeyColorNone:
+		c := color.New()
+		c.DisableColor()
+		return c
+	case KeyColor256:
+		palette := keyColor256Dark
+		if resolveBackground(bg) == BackgroundLight {
+			palette = keyColor256Light
+		}
+		return color256(palette[sum%uint32(len(palette))])
+	case KeyColor16:
+		palette := keyColor16Dark
+		if resolveBackground(bg) == BackgroundLight {
+			palette = keyColor16Light
+		}
+		return color.New(palette[sum%uint32(len(palette))])
+	default: // KeyColorTruecolor
+		r, g, b := mapColor(sum>>24), mapColor(sum>>16), mapColor(sum>>8)
+		return color.RGB(int(r), int(g), int(b))
+	}
+}
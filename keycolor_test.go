@@ -0,0 +1,80 @@
+package humanlog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// withColorForced temporarily forces color.NoColor to false so ANSI
+// sequences are emitted regardless of whether the test process is a
+// terminal, then restores the previous value.
+func withColorForced(t *testing.T, fn func()) {
+	t.Helper()
+	prev := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = prev }()
+	fn()
+}
+
+func TestHashedKeyColorNoneDisablesColor(t *testing.T) {
+	withColorForced(t, func() {
+		got := hashedKeyColor(12345, KeyColorNone, BackgroundAuto).Sprint("key")
+		if strings.Contains(got, "\x1b") {
+			t.Errorf("hashedKeyColor(KeyColorNone).Sprint = %q, want no ANSI escape sequence", got)
+		}
+		if got != "key" {
+			t.Errorf("hashedKeyColor(KeyColorNone).Sprint = %q, want %q", got, "key")
+		}
+	})
+}
+
+func TestGetKeyColorNoneIgnoresOverride(t *testing.T) {
+	f := newFormatter(nil)
+	withColorForced(t, func() {
+		kc := keyColorConfig{Mode: KeyColorNone, Colors: defaultKeyColors()}
+		got := f.getKeyColor("error", kc).Sprint("error")
+		if strings.Contains(got, "\x1b") {
+			t.Errorf("getKeyColor(%q, KeyColorNone) = %q, want no ANSI escape even with a Colors override", "error", got)
+		}
+	})
+}
+
+func TestHashedKeyColorModesAndBackgrounds(t *testing.T) {
+	cases := []struct {
+		mode KeyColorMode
+		bg   Background
+	}{
+		{KeyColorTruecolor, BackgroundDark},
+		{KeyColorTruecolor, BackgroundLight},
+		{KeyColor256, BackgroundDark},
+		{KeyColor256, BackgroundLight},
+		{KeyColor16, BackgroundDark},
+		{KeyColor16, BackgroundLight},
+	}
+	for _, c := range cases {
+		withColorForced(t, func() {
+			got := hashedKeyColor(999, c.mode, c.bg).Sprint("key")
+			if !strings.Contains(got, "\x1b") {
+				t.Errorf("hashedKeyColor(mode=%v, bg=%v).Sprint = %q, want an ANSI escape sequence", c.mode, c.bg, got)
+			}
+			if !strings.Contains(got, "key") {
+				t.Errorf("hashedKeyColor(mode=%v, bg=%v).Sprint = %q, want it to contain %q", c.mode, c.bg, got, "key")
+			}
+			again := hashedKeyColor(999, c.mode, c.bg).Sprint("key")
+			if got != again {
+				t.Errorf("hashedKeyColor(mode=%v, bg=%v) is not deterministic: %q != %q", c.mode, c.bg, got, again)
+			}
+		})
+	}
+}
+
+func TestResolveBackgroundNonAutoPassesThrough(t *testing.T) {
+	if got := resolveBackground(BackgroundDark); got != BackgroundDark {
+		t.Errorf("resolveBackground(BackgroundDark) = %v, want %v", got, BackgroundDark)
+	}
+	if got := resolveBackground(BackgroundLight); got != BackgroundLight {
+		t.Errorf("resolveBackground(BackgroundLight) = %v, want %v", got, BackgroundLight)
+	}
+}
@@ -0,0 +1,213 @@
+package humanlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+
+	"github.com/apex/log"
+	"github.com/fatih/color"
+)
+
+// SlogHandler implements [log/slog.Handler], rendering records the same way
+// [Handler] renders [github.com/apex/log.Entry] values, including the same
+// [Format] options.
+type SlogHandler struct {
+	*formatter
+	Timestamp string
+	// ShowCaller, when true, renders a "source"/"caller" attribute, or
+	// failing that the record's PC, in a fixed column after the level
+	// symbol. When false, such an attribute is rendered like any other.
+	ShowCaller bool
+	// CallerMarshalFunc formats caller info for display. Defaults to
+	// [DefaultCallerMarshalFunc].
+	CallerMarshalFunc CallerMarshalFunc
+	// Humanizers formats a named field's value before it is measured and
+	// rendered, keyed by field name.
+	Humanizers map[string]func(interface{}) interface{}
+	// AutoHumanize picks a humanizer based on key-name heuristics (bytes,
+	// size, duration, rate, count, ratio, percent) for fields with no
+	// matching entry in Humanizers.
+	AutoHumanize bool
+	// KeyColorMode selects how field-name colors are rendered. Defaults to
+	// [KeyColorTruecolor].
+	KeyColorMode KeyColorMode
+	// KeyColors overrides the hashed color for specific field names,
+	// keyed by field name. Seeded with an "error" -> red entry.
+	KeyColors map[string]*color.Color
+	// Background selects which curated [KeyColor256]/[KeyColor16] palette
+	// to draw from. Defaults to [BackgroundAuto].
+	Background Background
+	// Format selects the output format. Defaults to [FormatAuto].
+	Format Format
+
+	isTTY bool
+
+	groupPrefix string
+	attrs       []slogField
+}
+
+type slogField struct {
+	Name  string
+	Value interface{}
+}
+
+// NewSlog returns a new [SlogHandler] writing to [w].
+func NewSlog(w io.Writer) *SlogHandler {
+	return &SlogHandler{
+		formatter:         newFormatter(w),
+		Timestamp:         "15:04:05.000",
+		CallerMarshalFunc: DefaultCallerMarshalFunc,
+		KeyColors:         defaultKeyColors(),
+		isTTY:             isTerminal(w),
+	}
+}
+
+// keyColorConfig bundles h's key-coloring options for writeNameValue.
+func (h *SlogHandler) keyColorConfig() keyColorConfig {
+	return keyColorConfig{Mode: h.KeyColorMode, Colors: h.KeyColors, Background: h.Background}
+}
+
+// Enabled implements [log/slog.Handler]. All levels are enabled; filtering is
+// left to the [log/slog.Logger] or a wrapping handler.
+func (h *SlogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements [log/slog.Handler].
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	lvl := levelFromSlog(r.Level)
+
+	fields := append([]slogField{}, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.groupPrefix, a)
+		return true
+	})
+	values := make(map[string]interface{}, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, fl := range fields {
+		values[fl.Name] = fl.Value
+		names = append(names, fl.Name)
+	}
+	get := func(name string) interface{} { return values[name] }
+	sort.Strings(names)
+
+	switch resolveFormat(h.Format, h.isTTY) {
+	case FormatJSON:
+		return h.writeJSON(r.Time, lvl.String(), r.Message, names, get)
+	case FormatLogfmt:
+		return h.writeLogfmt(r.Time, lvl.String(), r.Message, names, get, h.Humanizers, h.AutoHumanize)
+	}
+
+	names = h.sortNames(names, get)
+
+	colr := LevelColors[lvl]
+	symbol := LevelSymbol[lvl]
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buf.Reset()
+	if err := h.writeHeader(colr, r.Time.Format(h.Timestamp), symbol, r.Message); err != nil {
+		return err
+	}
+	var callerKey string
+	if h.ShowCaller {
+		var caller string
+		for _, key := range callerFieldNames {
+			if val, ok := values[key]; ok {
+				callerKey = key
+				caller = callerString(h.CallerMarshalFunc, val)
+				break
+			}
+		}
+		if caller == "" && r.PC != 0 {
+			caller = marshalPC(h.CallerMarshalFunc, r.PC)
+		}
+		if err := h.writeCaller(caller); err != nil {
+			return err
+		}
+	}
+	for i, name := range names {
+		if name == callerKey {
+			continue
+		}
+		if err := h.writeNameValue(name, values[name], i, len(names), h.Humanizers, h.AutoHumanize, h.keyColorConfig()); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(h.buf)
+	if err != nil {
+		return err
+	}
+	_, err = h.buf.WriteTo(h.Writer)
+	return err
+}
+
+// WithAttrs implements [log/slog.Handler].
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	nh := *h
+	nh.attrs = append([]slogField{}, h.attrs...)
+	for _, a := range attrs {
+		nh.attrs = appendSlogAttr(nh.attrs, h.groupPrefix, a)
+	}
+	return &nh
+}
+
+// WithGroup implements [log/slog.Handler]. Keys of attributes added after a
+// group is opened are prefixed with the group name and a dot.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.groupPrefix = joinSlogKey(h.groupPrefix, name)
+	return &nh
+}
+
+// levelFromSlog maps a [log/slog.Level] onto the existing [log.Level] scale,
+// bucketing custom levels between the four standard ones.
+func levelFromSlog(l slog.Level) log.Level {
+	switch {
+	case l < slog.LevelInfo:
+		return log.DebugLevel
+	case l < slog.LevelWarn:
+		return log.InfoLevel
+	case l < slog.LevelError:
+		return log.WarnLevel
+	default:
+		return log.ErrorLevel
+	}
+}
+
+// appendSlogAttr flattens a into fields, descending into groups and
+// prefixing their keys with prefix.
+func appendSlogAttr(fields []slogField, prefix string, a slog.Attr) []slogField {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = joinSlogKey(prefix, a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSlogAttr(fields, groupPrefix, ga)
+		}
+		return fields
+	}
+	if a.Key == "" {
+		return fields
+	}
+	return append(fields, slogField{Name: joinSlogKey(prefix, a.Key), Value: a.Value.Any()})
+}
+
+func joinSlogKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
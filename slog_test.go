@@ -0,0 +1,89 @@
+package humanlog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandlerHandleTextMultiField(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSlog(&buf)
+	h.Format = FormatText
+	err := h.Handle(context.Background(), slogRecord(testTime, slog.LevelInfo, "hi", slog.String("user", "bob"), slog.Int("n", 3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "12:00:00.000 I hi n=3 user=bob\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Handle text output = %q, want %q", got, want)
+	}
+}
+
+func TestSlogHandlerWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSlog(&buf)
+	h.Format = FormatLogfmt
+	h2 := h.WithAttrs([]slog.Attr{slog.String("user", "bob")})
+	err := h2.Handle(context.Background(), slogRecord(testTime, slog.LevelInfo, "hi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `time=2026-07-30T12:00:00Z level=info msg=hi user=bob` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Handle after WithAttrs = %q, want %q", got, want)
+	}
+	// The original handler must be unaffected by WithAttrs.
+	buf.Reset()
+	if err := h.Handle(context.Background(), slogRecord(testTime, slog.LevelInfo, "hi")); err != nil {
+		t.Fatal(err)
+	}
+	want = `time=2026-07-30T12:00:00Z level=info msg=hi` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("original handler after WithAttrs = %q, want %q", got, want)
+	}
+}
+
+func TestSlogHandlerWithGroup(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSlog(&buf)
+	h.Format = FormatLogfmt
+	h2 := h.WithGroup("req").(*SlogHandler)
+	err := h2.Handle(context.Background(), slogRecord(testTime, slog.LevelInfo, "hi", slog.String("method", "GET")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `time=2026-07-30T12:00:00Z level=info msg=hi req.method=GET` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Handle after WithGroup = %q, want %q", got, want)
+	}
+}
+
+func TestSlogHandlerWithGroupEmptyNameNoop(t *testing.T) {
+	h := NewSlog(&bytes.Buffer{})
+	if h.WithGroup("") != h {
+		t.Error("WithGroup(\"\") should return the same handler")
+	}
+}
+
+func TestLevelFromSlog(t *testing.T) {
+	cases := []struct {
+		level slog.Level
+		want  string
+	}{
+		{slog.LevelDebug, "debug"},
+		{slog.LevelDebug + 3, "debug"},
+		{slog.LevelInfo, "info"},
+		{slog.LevelInfo + 3, "info"},
+		{slog.LevelWarn, "warn"},
+		{slog.LevelWarn + 3, "warn"},
+		{slog.LevelError, "error"},
+		{slog.LevelError + 10, "error"},
+	}
+	for _, c := range cases {
+		if got := levelFromSlog(c.level).String(); got != c.want {
+			t.Errorf("levelFromSlog(%v) = %q, want %q", c.level, got, c.want)
+		}
+	}
+}